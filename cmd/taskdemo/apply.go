@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mark-rushakoff/taskdemo/internal/tasks"
+)
+
+func newApplyCmd() *cobra.Command {
+	var file string
+	var print bool
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Create or update tasks from a declarative YAML spec",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			specs, err := tasks.ParseSpecFile(file)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			s, err := newServices(ctx)
+			if err != nil {
+				return err
+			}
+
+			return tasks.Apply(ctx, s, specs, tasks.ApplyOptions{Print: print}, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "YAML file containing one or more task specs (required)")
+	cmd.Flags().BoolVar(&print, "print", false, "print the generated Flux instead of submitting it")
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
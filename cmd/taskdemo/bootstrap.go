@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mark-rushakoff/taskdemo/internal/authz"
+	"github.com/mark-rushakoff/taskdemo/internal/bootstrap"
+	"github.com/mark-rushakoff/taskdemo/internal/dataflow"
+)
+
+func newBootstrapCmd() *cobra.Command {
+	var retentionIn, retentionOut time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "bootstrap",
+		Short: "Create the org, user, buckets, and authorizations for the namespace",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			s, err := newServices(ctx)
+			if err != nil {
+				return err
+			}
+			return bootstrap.Run(ctx, s, bootstrap.Retentions{
+				In:  retentionIn,
+				Out: retentionOut,
+			})
+		},
+	}
+
+	cmd.Flags().DurationVar(&retentionIn, "retention-in", time.Hour, "retention period for the input bucket")
+	cmd.Flags().DurationVar(&retentionOut, "retention-out", 24*time.Hour, "retention period for the output bucket")
+
+	return cmd
+}
+
+func newListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the entities created for the namespace",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			s, err := newServices(ctx)
+			if err != nil {
+				return err
+			}
+			return bootstrap.List(ctx, s)
+		},
+	}
+}
+
+func newDestroyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "destroy",
+		Short: "Destroy everything created for the namespace",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			s, err := newServices(ctx)
+			if err != nil {
+				return err
+			}
+			return bootstrap.Destroy(ctx, s)
+		},
+	}
+}
+
+func newServices(ctx context.Context) (*dataflow.Services, error) {
+	ns, err := namespace()
+	if err != nil {
+		return nil, err
+	}
+	tok, err := token()
+	if err != nil {
+		return nil, err
+	}
+	s := dataflow.NewServices(viperAPI(), tok, ns)
+	s.Logger = logger
+
+	path, err := authz.FilePath(ns)
+	if err != nil {
+		return nil, err
+	}
+	store, err := authz.NewFileStore(path, viper.GetString("token-store-passphrase"), s.Auths, s.UserID)
+	if err != nil {
+		return nil, err
+	}
+	s.Tokens = store
+
+	return s, nil
+}
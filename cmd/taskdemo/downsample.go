@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mark-rushakoff/taskdemo/internal/dataflow"
+)
+
+func newDownsampleOnceCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "downsample-once",
+		Short: "Manually downsample once from the input bucket to the output bucket",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			s, err := newServices(ctx)
+			if err != nil {
+				return err
+			}
+			return dataflow.DownsampleOnce(ctx, s, "-5s", os.Stdout)
+		},
+	}
+}
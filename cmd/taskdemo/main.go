@@ -0,0 +1,124 @@
+// Command taskdemo drives an InfluxDB platform instance through the
+// bootstrap/write/downsample/task workflow used to demo tasks.
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/mark-rushakoff/taskdemo/internal/logging"
+	"github.com/mark-rushakoff/taskdemo/internal/metrics"
+)
+
+// logger and metricsServer are set up in the root command's
+// PersistentPreRunE, once flags and config are resolved. logger is handed to
+// every subcommand through newServices; metricsServer is shut down in
+// PersistentPostRun.
+var (
+	logger        *zap.SugaredLogger
+	metricsServer *http.Server
+)
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func newRootCmd() *cobra.Command {
+	root := &cobra.Command{
+		Use:           "taskdemo",
+		Short:         "Drive an InfluxDB platform instance through a tasks demo workflow",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			l, err := logging.New(viper.GetString("log-format"), viper.GetString("log-level"))
+			if err != nil {
+				return err
+			}
+			logger = l
+
+			if addr := viper.GetString("metrics-addr"); addr != "" {
+				metricsServer = metrics.Serve(addr)
+				logger.Infow("Serving metrics", "addr", addr)
+			}
+			return nil
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			if metricsServer != nil {
+				metrics.Shutdown(metricsServer)
+			}
+		},
+	}
+
+	root.PersistentFlags().String("api", "http://localhost:9999", "HTTP endpoint of API server")
+	root.PersistentFlags().String("namespace", "", "namespace to scope the demo user, org, and buckets (required)")
+	root.PersistentFlags().String("token", "", "bootstrap token; defaults to $TASKDEMO_TOKEN")
+	root.PersistentFlags().String("token-store-passphrase", "", "passphrase to encrypt the local token store with; defaults to $TASKDEMO_TOKEN_STORE_PASSPHRASE")
+	root.PersistentFlags().String("log-format", "console", "log output format: json or console")
+	root.PersistentFlags().String("log-level", "info", "log level: debug, info, warn, or error")
+	root.PersistentFlags().String("metrics-addr", "", "address to serve Prometheus /metrics on, e.g. :9090; empty disables it")
+
+	viper.BindPFlag("api", root.PersistentFlags().Lookup("api"))
+	viper.BindPFlag("namespace", root.PersistentFlags().Lookup("namespace"))
+	viper.BindPFlag("token", root.PersistentFlags().Lookup("token"))
+	viper.BindPFlag("token-store-passphrase", root.PersistentFlags().Lookup("token-store-passphrase"))
+	viper.BindPFlag("log-format", root.PersistentFlags().Lookup("log-format"))
+	viper.BindPFlag("log-level", root.PersistentFlags().Lookup("log-level"))
+	viper.BindPFlag("metrics-addr", root.PersistentFlags().Lookup("metrics-addr"))
+
+	viper.SetEnvPrefix("taskdemo")
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	viper.AutomaticEnv()
+
+	viper.SetConfigName("config")
+	viper.SetConfigType("yaml")
+	if cfgDir, err := os.UserConfigDir(); err == nil {
+		viper.AddConfigPath(cfgDir + "/taskdemo")
+	}
+	// Ignore a missing config file; flags and env vars are enough on their own.
+	_ = viper.ReadInConfig()
+
+	root.AddCommand(
+		newBootstrapCmd(),
+		newListCmd(),
+		newWriteCmd(),
+		newReadInCmd(),
+		newReadOutCmd(),
+		newDownsampleOnceCmd(),
+		newCreateTaskCmd(),
+		newApplyCmd(),
+		newDestroyCmd(),
+		newTokensCmd(),
+		newVerifyCmd(),
+	)
+
+	return root
+}
+
+func namespace() (string, error) {
+	ns := viper.GetString("namespace")
+	if ns == "" {
+		return "", fmt.Errorf("namespace is required; set --namespace, TASKDEMO_NAMESPACE, or namespace in the config file")
+	}
+	return ns, nil
+}
+
+func token() (string, error) {
+	t := viper.GetString("token")
+	if t == "" {
+		return "", fmt.Errorf("token is required; set --token, TASKDEMO_TOKEN, or token in the config file")
+	}
+	return t, nil
+}
+
+func viperAPI() string {
+	return viper.GetString("api")
+}
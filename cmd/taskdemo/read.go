@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mark-rushakoff/taskdemo/internal/dataflow"
+)
+
+func newReadInCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "read-in",
+		Short: "Read recent data from the input bucket",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			s, err := newServices(ctx)
+			if err != nil {
+				return err
+			}
+			return dataflow.ReadOnce(ctx, s, s.BucketInName(), "-5s", os.Stdout)
+		},
+	}
+}
+
+func newReadOutCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "read-out",
+		Short: "Read recent data from the output bucket",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			s, err := newServices(ctx)
+			if err != nil {
+				return err
+			}
+			return dataflow.ReadOnce(ctx, s, s.BucketOutName(), "-15s", os.Stdout)
+		},
+	}
+}
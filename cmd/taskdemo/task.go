@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mark-rushakoff/taskdemo/internal/tasks"
+)
+
+func newCreateTaskCmd() *cobra.Command {
+	var cadence time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "create-task",
+		Short: "Create a task that continually downsamples from the input bucket to the output bucket",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			s, err := newServices(ctx)
+			if err != nil {
+				return err
+			}
+			return tasks.Create(ctx, s, cadence)
+		},
+	}
+
+	cmd.Flags().DurationVar(&cadence, "every", 5*time.Second, "how often the task runs")
+
+	return cmd
+}
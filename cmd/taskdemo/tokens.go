@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mark-rushakoff/taskdemo/internal/authz"
+)
+
+func newTokensCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tokens",
+		Short: "List, prune, and re-mint the locally cached tokens for the namespace",
+	}
+
+	cmd.AddCommand(newTokensListCmd(), newTokensPruneCmd(), newTokensRefreshCmd())
+
+	return cmd
+}
+
+func newTokensListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the cached token entries for the namespace",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			s, err := newServices(ctx)
+			if err != nil {
+				return err
+			}
+			store, ok := s.Tokens.(*authz.FileStore)
+			if !ok {
+				return fmt.Errorf("no token store configured")
+			}
+
+			entries := store.Entries()
+			keys := make([]string, 0, len(entries))
+			for k := range entries {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			for _, k := range keys {
+				e := entries[k]
+				fmt.Printf("%s:\n", k)
+				fmt.Printf("\tToken: %s\n", maskToken(e.Token))
+				for _, p := range e.Permissions {
+					fmt.Printf("\tPermission: action=%s Resource=%s\n", p.Action, p.Resource)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func newTokensPruneCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "prune",
+		Short: "Drop cached entries whose authorization no longer exists",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			s, err := newServices(ctx)
+			if err != nil {
+				return err
+			}
+			store, ok := s.Tokens.(*authz.FileStore)
+			if !ok {
+				return fmt.Errorf("no token store configured")
+			}
+			return store.Prune(ctx)
+		},
+	}
+}
+
+func newTokensRefreshCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "refresh",
+		Short: "Re-mint every cached token from the API",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			s, err := newServices(ctx)
+			if err != nil {
+				return err
+			}
+			return s.Tokens.Refresh(ctx)
+		},
+	}
+}
+
+func maskToken(t string) string {
+	if len(t) <= 8 {
+		return "****"
+	}
+	return t[:4] + "..." + t[len(t)-4:]
+}
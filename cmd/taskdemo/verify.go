@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mark-rushakoff/taskdemo/internal/verify"
+)
+
+func newVerifyCmd() *cobra.Command {
+	var (
+		window     time.Duration
+		numWindows int
+		aggregate  string
+		tolerance  float64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Assert that the output bucket's downsampled data matches the expected aggregate",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			s, err := newServices(ctx)
+			if err != nil {
+				return err
+			}
+
+			report, err := verify.Run(ctx, s, verify.Options{
+				Window:     window,
+				NumWindows: numWindows,
+				Aggregate:  aggregate,
+				Tolerance:  tolerance,
+			})
+			if err != nil {
+				return err
+			}
+			if !report.Pass {
+				failed := 0
+				for _, w := range report.Windows {
+					if !w.Pass {
+						failed++
+					}
+				}
+				return fmt.Errorf("verify failed: %d of %d windows did not match", failed, len(report.Windows))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&window, "window", 5*time.Second, "size of each downsample window to check")
+	cmd.Flags().IntVar(&numWindows, "windows", 5, "how many of the most recent windows to check")
+	cmd.Flags().StringVar(&aggregate, "aggregate", "last", "expected aggregate function, e.g. last, mean, max, sum, percentile:95")
+	cmd.Flags().Float64Var(&tolerance, "tolerance", 0.01, "maximum allowed absolute difference between expected and actual")
+
+	return cmd
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mark-rushakoff/taskdemo/internal/dataflow"
+)
+
+func newWriteCmd() *cobra.Command {
+	var (
+		measurement string
+		tagSpec     string
+		fieldSpec   string
+		rate        float64
+		batchSize   int
+		concurrency int
+		duration    time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "write",
+		Short: "Write to the input bucket, optionally at a bounded rate and concurrency",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tags, err := dataflow.ParseTags(tagSpec)
+			if err != nil {
+				return err
+			}
+			fields, err := dataflow.ParseFields(fieldSpec)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT)
+			defer stop()
+
+			s, err := newServices(ctx)
+			if err != nil {
+				return err
+			}
+
+			_, err = dataflow.Write(ctx, s, dataflow.WriteOptions{
+				Measurement: measurement,
+				Tags:        tags,
+				Fields:      fields,
+				Rate:        rate,
+				BatchSize:   batchSize,
+				Concurrency: concurrency,
+				Duration:    duration,
+			})
+			if err == context.Canceled {
+				return nil
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&measurement, "measurement", "counter", "measurement name to write")
+	cmd.Flags().StringVar(&tagSpec, "tags", "", "comma-separated key=val tags, e.g. host=a,region=us")
+	cmd.Flags().StringVar(&fieldSpec, "fields", "n=counter:1",
+		"comma-separated name=generator field spec; generators: counter[:step], gauge-random[:min:max], sine[:amplitude:period], gaussian[:mean:stddev]")
+	cmd.Flags().Float64Var(&rate, "rate", 10, "points/sec to write, across all goroutines; 0 means unlimited")
+	cmd.Flags().IntVar(&batchSize, "batch-size", 1, "points coalesced into a single write request")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 1, "number of goroutines writing concurrently")
+	cmd.Flags().DurationVar(&duration, "duration", 0, "how long to write for; 0 means run until interrupted")
+
+	return cmd
+}
@@ -0,0 +1,37 @@
+// Package authz resolves tokens for a user that satisfy a requested set of
+// platform permissions.
+package authz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/platform"
+	phttp "github.com/influxdata/platform/http"
+)
+
+// FindToken scans the authorizations belonging to userID and returns the
+// token of the first one allowing every permission in perms.
+func FindToken(ctx context.Context, auths phttp.AuthorizationService, userID platform.ID, perms ...platform.Permission) (string, error) {
+	as, _, err := auths.FindAuthorizations(ctx, platform.AuthorizationFilter{
+		UserID: &userID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to find authorizations for user with ID %s: %w", userID.String(), err)
+	}
+
+	for _, a := range as {
+		allowed := true
+		for _, p := range perms {
+			if !a.Allowed(p) {
+				allowed = false
+				break
+			}
+		}
+		if allowed {
+			return a.Token, nil
+		}
+	}
+
+	return "", fmt.Errorf("no authorization for user with ID %s grants the requested %d permission(s)", userID.String(), len(perms))
+}
@@ -0,0 +1,262 @@
+package authz
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/nacl/secretbox"
+
+	"github.com/influxdata/platform"
+	phttp "github.com/influxdata/platform/http"
+)
+
+// TokenStore resolves tokens for a set of permissions, caching them locally
+// so that demos do not need to rescan authorizations on every command.
+type TokenStore interface {
+	// TokenFor returns a token allowing every permission in perms, minting
+	// an entry from the API on a cache miss.
+	TokenFor(ctx context.Context, perms ...platform.Permission) (string, error)
+
+	// Refresh re-validates every cached entry against the API, dropping any
+	// whose authorization no longer exists.
+	Refresh(ctx context.Context) error
+}
+
+// storeEntry is a single cached token, along with the permissions it was
+// minted for.
+type storeEntry struct {
+	Token       string                `json:"token"`
+	Permissions []platform.Permission `json:"permissions"`
+}
+
+// FileStore is a TokenStore backed by a local JSON file, optionally
+// encrypted with a passphrase-derived key.
+type FileStore struct {
+	path       string
+	passphrase string
+
+	auths         phttp.AuthorizationService
+	resolveUserID func(context.Context) (platform.ID, error)
+
+	entries map[string]storeEntry
+}
+
+// FilePath returns the JSON file used to store taskdemo's cached tokens for
+// namespace, under $XDG_CONFIG_HOME/taskdemo.
+func FilePath(namespace string) (string, error) {
+	cfgDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve config dir: %w", err)
+	}
+	return filepath.Join(cfgDir, "taskdemo", namespace+".json"), nil
+}
+
+// NewFileStore loads (or initializes) the token store at path, which is
+// read and rewritten with file mode 0600. If passphrase is non-empty, the
+// file is encrypted at rest with a key derived from it via argon2id.
+// resolveUserID is called lazily on a cache miss, rather than up front, so
+// that a store can be wired up before the demo user exists (e.g. during
+// bootstrap).
+func NewFileStore(path, passphrase string, auths phttp.AuthorizationService, resolveUserID func(context.Context) (platform.ID, error)) (*FileStore, error) {
+	fs := &FileStore{
+		path:          path,
+		passphrase:    passphrase,
+		auths:         auths,
+		resolveUserID: resolveUserID,
+		entries:       make(map[string]storeEntry),
+	}
+
+	if err := fs.load(); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+func (fs *FileStore) TokenFor(ctx context.Context, perms ...platform.Permission) (string, error) {
+	key := hashPermissions(perms)
+
+	if e, ok := fs.entries[key]; ok {
+		return e.Token, nil
+	}
+
+	userID, err := fs.resolveUserID(ctx)
+	if err != nil {
+		return "", err
+	}
+	token, err := FindToken(ctx, fs.auths, userID, perms...)
+	if err != nil {
+		return "", err
+	}
+
+	fs.entries[key] = storeEntry{Token: token, Permissions: perms}
+	if err := fs.save(); err != nil {
+		return "", fmt.Errorf("minted token but failed to persist it: %w", err)
+	}
+	return token, nil
+}
+
+func (fs *FileStore) Refresh(ctx context.Context) error {
+	userID, err := fs.resolveUserID(ctx)
+	if err != nil {
+		return err
+	}
+	for key, e := range fs.entries {
+		token, err := FindToken(ctx, fs.auths, userID, e.Permissions...)
+		if err != nil {
+			delete(fs.entries, key)
+			continue
+		}
+		e.Token = token
+		fs.entries[key] = e
+	}
+	return fs.save()
+}
+
+// Entries returns a snapshot of the cached permission-set hash to token
+// mappings, for the `taskdemo tokens` subcommand.
+func (fs *FileStore) Entries() map[string]storeEntry {
+	out := make(map[string]storeEntry, len(fs.entries))
+	for k, v := range fs.entries {
+		out[k] = v
+	}
+	return out
+}
+
+// Prune drops any cached entry whose permissions no longer resolve to a
+// live authorization, leaving surviving entries' cached tokens untouched.
+// Unlike Refresh, it never rewrites a token that's still valid.
+func (fs *FileStore) Prune(ctx context.Context) error {
+	userID, err := fs.resolveUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	pruned := false
+	for key, e := range fs.entries {
+		if _, err := FindToken(ctx, fs.auths, userID, e.Permissions...); err != nil {
+			delete(fs.entries, key)
+			pruned = true
+		}
+	}
+	if !pruned {
+		return nil
+	}
+	return fs.save()
+}
+
+func hashPermissions(perms []platform.Permission) string {
+	strs := make([]string, len(perms))
+	for i, p := range perms {
+		strs[i] = fmt.Sprintf("%s:%s", p.Action, p.Resource)
+	}
+	sort.Strings(strs)
+
+	h := sha256.New()
+	for _, s := range strs {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// onDiskFile is the JSON envelope written to disk. When Encrypted is true,
+// Data holds a secretbox-sealed ciphertext of the entries; otherwise Data
+// holds the entries' plain JSON encoding.
+type onDiskFile struct {
+	Encrypted bool   `json:"encrypted"`
+	Salt      []byte `json:"salt,omitempty"`
+	Nonce     []byte `json:"nonce,omitempty"`
+	Data      []byte `json:"data"`
+}
+
+func (fs *FileStore) load() error {
+	raw, err := ioutil.ReadFile(fs.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read token store %s: %w", fs.path, err)
+	}
+
+	var f onDiskFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return fmt.Errorf("failed to parse token store %s: %w", fs.path, err)
+	}
+
+	data := f.Data
+	if f.Encrypted {
+		if fs.passphrase == "" {
+			return fmt.Errorf("token store %s is encrypted but no passphrase was provided", fs.path)
+		}
+		var nonce [24]byte
+		copy(nonce[:], f.Nonce)
+		key := deriveKey(fs.passphrase, f.Salt)
+
+		opened, ok := secretbox.Open(nil, f.Data, &nonce, &key)
+		if !ok {
+			return fmt.Errorf("failed to decrypt token store %s: wrong passphrase?", fs.path)
+		}
+		data = opened
+	}
+
+	entries := make(map[string]storeEntry)
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return fmt.Errorf("failed to parse token store %s: %w", fs.path, err)
+		}
+	}
+	fs.entries = entries
+	return nil
+}
+
+func (fs *FileStore) save() error {
+	data, err := json.Marshal(fs.entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token store: %w", err)
+	}
+
+	f := onDiskFile{}
+	if fs.passphrase != "" {
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return fmt.Errorf("failed to generate salt: %w", err)
+		}
+		var nonce [24]byte
+		if _, err := rand.Read(nonce[:]); err != nil {
+			return fmt.Errorf("failed to generate nonce: %w", err)
+		}
+		key := deriveKey(fs.passphrase, salt)
+
+		f.Encrypted = true
+		f.Salt = salt
+		f.Nonce = nonce[:]
+		f.Data = secretbox.Seal(nil, data, &nonce, &key)
+	} else {
+		f.Data = data
+	}
+
+	out, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token store: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fs.path), 0700); err != nil {
+		return fmt.Errorf("failed to create token store directory: %w", err)
+	}
+	return ioutil.WriteFile(fs.path, out, 0600)
+}
+
+func deriveKey(passphrase string, salt []byte) [32]byte {
+	derived := argon2.IDKey([]byte(passphrase), salt, 1, 64*1024, 4, 32)
+	var key [32]byte
+	copy(key[:], derived)
+	return key
+}
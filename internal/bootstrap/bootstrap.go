@@ -0,0 +1,183 @@
+// Package bootstrap creates, lists, and destroys the demo user, org,
+// buckets, and authorizations for a taskdemo namespace.
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/platform"
+
+	"github.com/mark-rushakoff/taskdemo/internal/dataflow"
+)
+
+// Retentions configures the retention period applied to the input and
+// output buckets created by Run.
+type Retentions struct {
+	In  time.Duration
+	Out time.Duration
+}
+
+// Run creates the demo user, org, input/output buckets, and the
+// authorizations needed to exercise the rest of the demo.
+func Run(ctx context.Context, s *dataflow.Services, r Retentions) error {
+	u := &platform.User{Name: s.UserName()}
+	if err := s.Users.CreateUser(ctx, u); err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	s.Log().Infow("Created user", "name", u.Name, "id", u.ID.String())
+
+	o := &platform.Organization{Name: s.OrgName()}
+	if err := s.Orgs.CreateOrganization(ctx, o); err != nil {
+		return fmt.Errorf("failed to create org: %w", err)
+	}
+	s.Log().Infow("Created org", "name", o.Name, "id", o.ID.String())
+
+	bIn := &platform.Bucket{Name: s.BucketInName(), OrganizationID: o.ID, RetentionPeriod: r.In}
+	if err := s.Buckets.CreateBucket(ctx, bIn); err != nil {
+		return fmt.Errorf("failed to create bucket: %w", err)
+	}
+	s.Log().Infow("Created bucket", "name", bIn.Name, "id", bIn.ID.String())
+
+	bOut := &platform.Bucket{Name: s.BucketOutName(), OrganizationID: o.ID, RetentionPeriod: r.Out}
+	if err := s.Buckets.CreateBucket(ctx, bOut); err != nil {
+		return fmt.Errorf("failed to create bucket: %w", err)
+	}
+	s.Log().Infow("Created bucket", "name", bOut.Name, "id", bOut.ID.String())
+
+	authWriteIn := &platform.Authorization{
+		UserID: u.ID,
+		Permissions: []platform.Permission{
+			platform.WriteBucketPermission(bIn.ID),
+		},
+	}
+	if err := s.Auths.CreateAuthorization(ctx, authWriteIn); err != nil {
+		return fmt.Errorf("failed to create authorization to write to %s: %w", bIn.Name, err)
+	}
+	s.Log().Infow("Created authorization to write to bucket", "bucket", bIn.Name)
+
+	authReadIn := &platform.Authorization{
+		UserID: u.ID,
+		Permissions: []platform.Permission{
+			platform.ReadBucketPermission(bIn.ID),
+		},
+	}
+	if err := s.Auths.CreateAuthorization(ctx, authReadIn); err != nil {
+		return fmt.Errorf("failed to create authorization to read from %s: %w", bIn.Name, err)
+	}
+	s.Log().Infow("Created authorization to read from bucket", "bucket", bIn.Name)
+
+	authReadInWriteOutCreateTask := &platform.Authorization{
+		UserID: u.ID,
+		Permissions: []platform.Permission{
+			platform.ReadBucketPermission(bIn.ID),
+			platform.WriteBucketPermission(bOut.ID),
+			platform.Permission{Action: platform.CreateAction, Resource: platform.TaskResource(o.ID)},
+		},
+	}
+	if err := s.Auths.CreateAuthorization(ctx, authReadInWriteOutCreateTask); err != nil {
+		return fmt.Errorf("failed to create authorization to read from %s and write to %s: %w", bIn.Name, bOut.Name, err)
+	}
+	s.Log().Infow("Created authorization to read from bucket and write to bucket, and create tasks in org",
+		"readBucket", bIn.Name, "writeBucket", bOut.Name, "org", o.Name)
+
+	authReadOut := &platform.Authorization{
+		UserID: u.ID,
+		Permissions: []platform.Permission{
+			platform.ReadBucketPermission(bOut.ID),
+		},
+	}
+	if err := s.Auths.CreateAuthorization(ctx, authReadOut); err != nil {
+		return fmt.Errorf("failed to create authorization to read from %s: %w", bOut.Name, err)
+	}
+	s.Log().Infow("Created authorization to read from bucket", "bucket", bOut.Name)
+
+	if s.Tokens != nil {
+		for _, a := range []*platform.Authorization{authWriteIn, authReadIn, authReadInWriteOutCreateTask, authReadOut} {
+			if _, err := s.Tokens.TokenFor(ctx, a.Permissions...); err != nil {
+				return fmt.Errorf("failed to populate token store for authorization %s: %w", a.ID.String(), err)
+			}
+		}
+		s.Log().Infow("Populated token store with the authorizations created above")
+	}
+
+	return nil
+}
+
+// List logs the IDs of the entities created for the namespace, and the
+// authorizations granted to its demo user.
+func List(ctx context.Context, s *dataflow.Services) error {
+	uID, userErr := s.UserID(ctx)
+	if userErr == nil {
+		s.Log().Infow("User", "name", s.UserName(), "id", uID.String())
+	} else {
+		s.Log().Warnw("Could not find user; continuing", "name", s.UserName())
+	}
+
+	if oID, err := s.OrgID(ctx); err == nil {
+		s.Log().Infow("Org", "name", s.OrgName(), "id", oID.String())
+	} else {
+		s.Log().Warnw("Could not find org; continuing", "name", s.OrgName())
+	}
+
+	if bInID, err := s.BucketID(ctx, s.BucketInName()); err == nil {
+		s.Log().Infow("Bucket", "name", s.BucketInName(), "id", bInID.String())
+	} else {
+		s.Log().Warnw("Could not find bucket; continuing", "name", s.BucketInName())
+	}
+
+	if bOutID, err := s.BucketID(ctx, s.BucketOutName()); err == nil {
+		s.Log().Infow("Bucket", "name", s.BucketOutName(), "id", bOutID.String())
+	} else {
+		s.Log().Warnw("Could not find bucket; continuing", "name", s.BucketOutName())
+	}
+
+	if userErr != nil {
+		// No user, so no authorizations to look up.
+		return nil
+	}
+
+	as, _, err := s.Auths.FindAuthorizations(ctx, platform.AuthorizationFilter{
+		UserID: &uID,
+	})
+	if err != nil {
+		s.Log().Warnw("Could not find authorizations for user; continuing", "name", s.UserName())
+		return nil
+	}
+	for _, a := range as {
+		perms := make([]string, len(a.Permissions))
+		for i, p := range a.Permissions {
+			perms[i] = fmt.Sprintf("%s:%s", p.Action, p.Resource)
+		}
+		s.Log().Infow("Authorization", "id", a.ID.String(), "token", a.Token, "permissions", perms)
+	}
+	return nil
+}
+
+// Destroy deletes the demo user and org for the namespace.
+func Destroy(ctx context.Context, s *dataflow.Services) error {
+	uID, err := s.UserID(ctx)
+	if err == nil {
+		if err := s.Users.DeleteUser(ctx, uID); err == nil {
+			s.Log().Infow("Deleted user", "name", s.UserName())
+		} else {
+			s.Log().Warnw("Failed to delete user", "id", uID.String(), "error", err)
+		}
+	} else {
+		s.Log().Warnw("Could not find user; continuing", "name", s.UserName())
+	}
+
+	oID, err := s.OrgID(ctx)
+	if err == nil {
+		if err := s.Orgs.DeleteOrganization(ctx, oID); err == nil {
+			s.Log().Infow("Deleted org", "name", s.OrgName())
+		} else {
+			s.Log().Warnw("Failed to delete org", "id", oID.String(), "error", err)
+		}
+	} else {
+		s.Log().Warnw("Could not find org; continuing", "name", s.OrgName())
+	}
+
+	return nil
+}
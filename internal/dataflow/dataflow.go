@@ -0,0 +1,112 @@
+// Package dataflow holds the namespaced lookups shared by every taskdemo
+// command: resolving the demo user, org, and buckets for a namespace, and
+// wiring the platform HTTP services used to talk to them.
+package dataflow
+
+import (
+	"context"
+
+	"github.com/influxdata/platform"
+	phttp "github.com/influxdata/platform/http"
+	"go.uber.org/zap"
+
+	"github.com/mark-rushakoff/taskdemo/internal/authz"
+)
+
+// Services bundles the platform HTTP clients and namespace used by a single
+// taskdemo invocation.
+type Services struct {
+	APIEndpoint string
+	Namespace   string
+
+	Users   phttp.UserService
+	Orgs    phttp.OrganizationService
+	Buckets phttp.BucketService
+	Auths   phttp.AuthorizationService
+
+	// Tokens, if set, is consulted before falling back to Auths to resolve
+	// a token for a permission set. Commands that don't wire one up still
+	// work; they just scan authorizations on every call.
+	Tokens authz.TokenStore
+
+	// Logger, if set, receives structured logs from every command that logs
+	// through Log. A nil Logger falls back to a no-op logger.
+	Logger *zap.SugaredLogger
+}
+
+// Log returns s.Logger, or a no-op logger if none was configured. Every
+// command that takes a *Services should log through it rather than the
+// stdlib log package, so that --log-format/--log-level apply uniformly.
+func (s *Services) Log() *zap.SugaredLogger {
+	if s.Logger != nil {
+		return s.Logger
+	}
+	return zap.NewNop().Sugar()
+}
+
+// NewServices wires the platform HTTP services for the given API endpoint
+// and token.
+func NewServices(apiEndpoint, token, namespace string) *Services {
+	return &Services{
+		APIEndpoint: apiEndpoint,
+		Namespace:   namespace,
+
+		Users:   phttp.UserService{Addr: apiEndpoint, Token: token},
+		Orgs:    phttp.OrganizationService{Addr: apiEndpoint, Token: token},
+		Buckets: phttp.BucketService{Addr: apiEndpoint, Token: token},
+		Auths:   phttp.AuthorizationService{Addr: apiEndpoint, Token: token},
+	}
+}
+
+func (s *Services) UserName() string {
+	return "demo-user-" + s.Namespace
+}
+
+func (s *Services) OrgName() string {
+	return "demo-org-" + s.Namespace
+}
+
+func (s *Services) BucketInName() string {
+	return "demo-bucket-in-" + s.Namespace
+}
+
+func (s *Services) BucketOutName() string {
+	return "demo-bucket-out-" + s.Namespace
+}
+
+func (s *Services) UserID(ctx context.Context) (platform.ID, error) {
+	un := s.UserName()
+	u, err := s.Users.FindUser(ctx, platform.UserFilter{Name: &un})
+	if err != nil {
+		return platform.InvalidID(), err
+	}
+	return u.ID, nil
+}
+
+func (s *Services) OrgID(ctx context.Context) (platform.ID, error) {
+	on := s.OrgName()
+	o, err := s.Orgs.FindOrganization(ctx, platform.OrganizationFilter{Name: &on})
+	if err != nil {
+		return platform.InvalidID(), err
+	}
+	return o.ID, nil
+}
+
+func (s *Services) BucketID(ctx context.Context, name string) (platform.ID, error) {
+	on := s.OrgName()
+	b, err := s.Buckets.FindBucket(ctx, platform.BucketFilter{Name: &name, Organization: &on})
+	if err != nil {
+		return platform.InvalidID(), err
+	}
+	return b.ID, nil
+}
+
+// Token resolves a token for userID allowing every permission in perms,
+// preferring s.Tokens when set and falling back to scanning s.Auths on a
+// cache miss or when no token store is configured.
+func (s *Services) Token(ctx context.Context, userID platform.ID, perms ...platform.Permission) (string, error) {
+	if s.Tokens != nil {
+		return s.Tokens.TokenFor(ctx, perms...)
+	}
+	return authz.FindToken(ctx, s.Auths, userID, perms...)
+}
@@ -0,0 +1,79 @@
+package dataflow
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/platform"
+	phttp "github.com/influxdata/platform/http"
+	"github.com/influxdata/platform/query"
+
+	"github.com/mark-rushakoff/taskdemo/internal/metrics"
+)
+
+// DownsampleOnce runs the same last-value downsample that the create-task
+// command installs as a recurring task, but once, against startRange (a
+// Flux duration literal such as "-5s"). The result is written as CSV to w.
+func DownsampleOnce(ctx context.Context, s *Services, startRange string, w io.Writer) error {
+	oID, err := s.OrgID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find org %q: %w", s.OrgName(), err)
+	}
+	bInID, err := s.BucketID(ctx, s.BucketInName())
+	if err != nil {
+		return fmt.Errorf("failed to find bucket %q: %w", s.BucketInName(), err)
+	}
+	bOutID, err := s.BucketID(ctx, s.BucketOutName())
+	if err != nil {
+		return fmt.Errorf("failed to find bucket %q: %w", s.BucketOutName(), err)
+	}
+	uID, err := s.UserID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find user %q: %w", s.UserName(), err)
+	}
+	on := s.OrgName()
+
+	token, err := s.Token(ctx, uID,
+		platform.ReadBucketPermission(bInID), platform.WriteBucketPermission(bOutID))
+	if err != nil {
+		return fmt.Errorf("unable to find existing auth for user %q to read from bucket %q and write to bucket %q: %w",
+			s.UserName(), s.BucketInName(), s.BucketOutName(), err)
+	}
+
+	q := fmt.Sprintf(
+		`from(bucket:%q) |> range(start:%s) |> last() |> to(bucket:%q, org:%q) |> yield()`,
+		s.BucketInName(), startRange, s.BucketOutName(), on,
+	)
+
+	fqs := phttp.FluxQueryService{Addr: s.APIEndpoint, Token: token}
+
+	done := metrics.Track("downsample")
+	start := time.Now()
+	it, err := fqs.Query(ctx, &query.Request{
+		// FluxQueryService reads only Authorization.Token off the request to set
+		// the bearer header; the server re-checks permissions against the token
+		// itself, so a full *platform.Authorization isn't needed here.
+		Authorization:  &platform.Authorization{Token: token},
+		OrganizationID: oID,
+
+		Compiler: lang.FluxCompiler{Query: q},
+	})
+	latency := time.Since(start)
+	done()
+	if err != nil {
+		metrics.Observe("downsample", -1, 0, latency)
+		return fmt.Errorf("failed to query: %w", err)
+	}
+	metrics.Observe("downsample", 200, int64(len(q)), latency)
+	s.Log().Infow("Executed query", "query", q)
+
+	enc := csv.NewMultiResultEncoder(csv.DefaultEncoderConfig())
+	if _, err := enc.Encode(w, it); err != nil {
+		return fmt.Errorf("failed to encode csv: %w", err)
+	}
+	return nil
+}
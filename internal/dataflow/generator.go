@@ -0,0 +1,165 @@
+package dataflow
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Generator produces the next value for a field on each call to Next.
+type Generator interface {
+	Next() float64
+}
+
+// FieldSpec pairs a field name with the generator used to produce its
+// values.
+type FieldSpec struct {
+	Name string
+	Gen  Generator
+}
+
+// counterGenerator produces a monotonically increasing value, incrementing
+// by step on each call.
+type counterGenerator struct {
+	step, n float64
+}
+
+func (g *counterGenerator) Next() float64 {
+	n := g.n
+	g.n += g.step
+	return n
+}
+
+// gaugeRandomGenerator produces a uniformly distributed value in [min, max).
+type gaugeRandomGenerator struct {
+	min, max float64
+}
+
+func (g *gaugeRandomGenerator) Next() float64 {
+	return g.min + rand.Float64()*(g.max-g.min)
+}
+
+// sineGenerator produces a sine wave of the given amplitude and period,
+// sampled at the time Next is called.
+type sineGenerator struct {
+	amplitude float64
+	period    time.Duration
+	start     time.Time
+}
+
+func (g *sineGenerator) Next() float64 {
+	phase := float64(time.Since(g.start)) / float64(g.period) * 2 * math.Pi
+	return g.amplitude * math.Sin(phase)
+}
+
+// gaussianGenerator produces a normally distributed value with the given
+// mean and standard deviation.
+type gaussianGenerator struct {
+	mean, stddev float64
+}
+
+func (g *gaussianGenerator) Next() float64 {
+	return g.mean + rand.NormFloat64()*g.stddev
+}
+
+// ParseFields parses a comma-separated field spec such as
+// "n=counter:1,temp=gauge-random:60:80,wave=sine:10:30s,noise=gaussian:0:1"
+// into a slice of FieldSpec, one per field.
+func ParseFields(spec string) ([]FieldSpec, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("field spec must not be empty")
+	}
+
+	var fields []FieldSpec
+	for _, part := range strings.Split(spec, ",") {
+		nameAndGen := strings.SplitN(part, "=", 2)
+		if len(nameAndGen) != 2 {
+			return nil, fmt.Errorf("invalid field spec %q: expected name=generator", part)
+		}
+		name := nameAndGen[0]
+
+		genArgs := strings.Split(nameAndGen[1], ":")
+		gen, err := newGenerator(genArgs[0], genArgs[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid field spec for %q: %w", name, err)
+		}
+
+		fields = append(fields, FieldSpec{Name: name, Gen: gen})
+	}
+	return fields, nil
+}
+
+func newGenerator(kind string, args []string) (Generator, error) {
+	f := func(i int, def float64) (float64, error) {
+		if i >= len(args) {
+			return def, nil
+		}
+		return strconv.ParseFloat(args[i], 64)
+	}
+
+	switch kind {
+	case "counter":
+		step, err := f(0, 1)
+		if err != nil {
+			return nil, err
+		}
+		return &counterGenerator{step: step}, nil
+	case "gauge-random":
+		min, err := f(0, 0)
+		if err != nil {
+			return nil, err
+		}
+		max, err := f(1, 100)
+		if err != nil {
+			return nil, err
+		}
+		return &gaugeRandomGenerator{min: min, max: max}, nil
+	case "sine":
+		amplitude, err := f(0, 1)
+		if err != nil {
+			return nil, err
+		}
+		var period time.Duration
+		if len(args) > 1 {
+			period, err = time.ParseDuration(args[1])
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			period = time.Minute
+		}
+		return &sineGenerator{amplitude: amplitude, period: period, start: time.Now()}, nil
+	case "gaussian":
+		mean, err := f(0, 0)
+		if err != nil {
+			return nil, err
+		}
+		stddev, err := f(1, 1)
+		if err != nil {
+			return nil, err
+		}
+		return &gaussianGenerator{mean: mean, stddev: stddev}, nil
+	default:
+		return nil, fmt.Errorf("unknown generator %q", kind)
+	}
+}
+
+// ParseTags parses a comma-separated tag spec such as "host=a,region=us" into
+// a map of tag key to value.
+func ParseTags(spec string) (map[string]string, error) {
+	tags := make(map[string]string)
+	if spec == "" {
+		return tags, nil
+	}
+	for _, part := range strings.Split(spec, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid tag %q: expected key=val", part)
+		}
+		tags[kv[0]] = kv[1]
+	}
+	return tags, nil
+}
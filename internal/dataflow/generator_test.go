@@ -0,0 +1,73 @@
+package dataflow
+
+import "testing"
+
+func TestParseFieldsCounter(t *testing.T) {
+	fields, err := ParseFields("n=counter:2")
+	if err != nil {
+		t.Fatalf("ParseFields returned %v", err)
+	}
+	if len(fields) != 1 || fields[0].Name != "n" {
+		t.Fatalf("ParseFields = %+v, want one field named n", fields)
+	}
+
+	g := fields[0].Gen
+	if got := g.Next(); got != 0 {
+		t.Errorf("first Next() = %v, want 0", got)
+	}
+	if got := g.Next(); got != 2 {
+		t.Errorf("second Next() = %v, want 2", got)
+	}
+}
+
+func TestParseFieldsGaugeRandomBounds(t *testing.T) {
+	fields, err := ParseFields("temp=gauge-random:60:80")
+	if err != nil {
+		t.Fatalf("ParseFields returned %v", err)
+	}
+	g := fields[0].Gen
+	for i := 0; i < 100; i++ {
+		v := g.Next()
+		if v < 60 || v >= 80 {
+			t.Fatalf("Next() = %v, want in [60, 80)", v)
+		}
+	}
+}
+
+func TestParseFieldsUnknownGenerator(t *testing.T) {
+	if _, err := ParseFields("n=bogus"); err == nil {
+		t.Fatal("ParseFields with unknown generator kind returned nil error")
+	}
+}
+
+func TestParseFieldsInvalidSpec(t *testing.T) {
+	if _, err := ParseFields("noequalsign"); err == nil {
+		t.Fatal("ParseFields with no '=' returned nil error")
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	tags, err := ParseTags("host=a,region=us")
+	if err != nil {
+		t.Fatalf("ParseTags returned %v", err)
+	}
+	want := map[string]string{"host": "a", "region": "us"}
+	if len(tags) != len(want) {
+		t.Fatalf("ParseTags = %+v, want %+v", tags, want)
+	}
+	for k, v := range want {
+		if tags[k] != v {
+			t.Errorf("tags[%q] = %q, want %q", k, tags[k], v)
+		}
+	}
+}
+
+func TestParseTagsEmpty(t *testing.T) {
+	tags, err := ParseTags("")
+	if err != nil {
+		t.Fatalf("ParseTags(\"\") returned %v", err)
+	}
+	if len(tags) != 0 {
+		t.Errorf("ParseTags(\"\") = %+v, want empty map", tags)
+	}
+}
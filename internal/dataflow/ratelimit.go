@@ -0,0 +1,73 @@
+package dataflow
+
+import (
+	"context"
+	"time"
+)
+
+// rateLimiter is a simple token-bucket limiter shared by every write
+// goroutine, capping the aggregate point rate across all of them.
+type rateLimiter struct {
+	tokens chan struct{}
+	done   chan struct{}
+}
+
+// newRateLimiter starts a limiter that admits ratePerSec tokens per second,
+// with burst capacity for up to one second's worth of tokens. A ratePerSec
+// of zero or less disables limiting.
+func newRateLimiter(ratePerSec float64) *rateLimiter {
+	if ratePerSec <= 0 {
+		return nil
+	}
+
+	burst := int(ratePerSec)
+	if burst < 1 {
+		burst = 1
+	}
+	rl := &rateLimiter{
+		tokens: make(chan struct{}, burst),
+		done:   make(chan struct{}),
+	}
+
+	interval := time.Duration(float64(time.Second) / ratePerSec)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-rl.done:
+				return
+			case <-ticker.C:
+				select {
+				case rl.tokens <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	return rl
+}
+
+// Wait blocks until a token is available or ctx is done. A nil rateLimiter
+// never blocks.
+func (rl *rateLimiter) Wait(ctx context.Context) error {
+	if rl == nil {
+		return nil
+	}
+	select {
+	case <-rl.tokens:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stop releases the limiter's background goroutine. Stop on a nil
+// rateLimiter is a no-op.
+func (rl *rateLimiter) Stop() {
+	if rl == nil {
+		return
+	}
+	close(rl.done)
+}
@@ -0,0 +1,53 @@
+package dataflow
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNewRateLimiterDisabled(t *testing.T) {
+	for _, rate := range []float64{0, -1} {
+		if rl := newRateLimiter(rate); rl != nil {
+			t.Errorf("newRateLimiter(%v) = %v, want nil", rate, rl)
+		}
+	}
+}
+
+func TestRateLimiterWaitOnNilNeverBlocks(t *testing.T) {
+	var rl *rateLimiter
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := rl.Wait(ctx); err != nil {
+		t.Fatalf("Wait on nil limiter returned %v, want nil", err)
+	}
+}
+
+func TestRateLimiterAdmitsAboutRatePerSec(t *testing.T) {
+	const rate = 50.0
+	rl := newRateLimiter(rate)
+	defer rl.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	n := 0
+	for {
+		if err := rl.Wait(ctx); err != nil {
+			break
+		}
+		n++
+	}
+
+	// Allow generous slack for scheduling jitter in CI: the limiter should
+	// admit roughly rate tokens over one second, not an order of magnitude
+	// more or fewer.
+	if n < rate/2 || n > rate*2 {
+		t.Errorf("admitted %d tokens in ~1s at rate %v/s, want roughly %v", n, rate, rate)
+	}
+}
+
+func TestRateLimiterStopIsIdempotentOnNil(t *testing.T) {
+	var rl *rateLimiter
+	rl.Stop() // must not panic
+}
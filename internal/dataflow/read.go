@@ -0,0 +1,68 @@
+package dataflow
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/platform"
+	phttp "github.com/influxdata/platform/http"
+	"github.com/influxdata/platform/query"
+
+	"github.com/mark-rushakoff/taskdemo/internal/metrics"
+)
+
+// ReadOnce runs a single range query against bucketName starting at
+// startRange (a Flux duration literal such as "-5s") and writes the result
+// as CSV to w.
+func ReadOnce(ctx context.Context, s *Services, bucketName, startRange string, w io.Writer) error {
+	oID, err := s.OrgID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find org %q: %w", s.OrgName(), err)
+	}
+	bID, err := s.BucketID(ctx, bucketName)
+	if err != nil {
+		return fmt.Errorf("failed to find bucket %q: %w", bucketName, err)
+	}
+	uID, err := s.UserID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find user %q: %w", s.UserName(), err)
+	}
+
+	token, err := s.Token(ctx, uID, platform.ReadBucketPermission(bID))
+	if err != nil {
+		return fmt.Errorf("unable to find existing auth for user %q to read from bucket %q: %w", s.UserName(), bucketName, err)
+	}
+
+	q := fmt.Sprintf("from(bucket:%q) |> range(start:%s)", bucketName, startRange)
+	fqs := phttp.FluxQueryService{Addr: s.APIEndpoint, Token: token}
+
+	done := metrics.Track("read")
+	start := time.Now()
+	it, err := fqs.Query(ctx, &query.Request{
+		// FluxQueryService reads only Authorization.Token off the request to set
+		// the bearer header; the server re-checks permissions against the token
+		// itself, so a full *platform.Authorization isn't needed here.
+		Authorization:  &platform.Authorization{Token: token},
+		OrganizationID: oID,
+
+		Compiler: lang.FluxCompiler{Query: q},
+	})
+	latency := time.Since(start)
+	done()
+	if err != nil {
+		metrics.Observe("read", -1, 0, latency)
+		return fmt.Errorf("failed to query: %w", err)
+	}
+	metrics.Observe("read", 200, int64(len(q)), latency)
+	s.Log().Infow("Executed query", "query", q)
+
+	enc := csv.NewMultiResultEncoder(csv.DefaultEncoderConfig())
+	if _, err := enc.Encode(w, it); err != nil {
+		return fmt.Errorf("failed to encode csv: %w", err)
+	}
+	return nil
+}
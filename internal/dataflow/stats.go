@@ -0,0 +1,101 @@
+package dataflow
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WriteStats accumulates throughput and latency observations across every
+// write goroutine, for reporting once a Write call finishes.
+type WriteStats struct {
+	mu sync.Mutex
+
+	requestsByStatus map[int]int
+	errors           int
+	bytesSent        int64
+	latencies        []time.Duration
+}
+
+func newWriteStats() *WriteStats {
+	return &WriteStats{requestsByStatus: make(map[int]int)}
+}
+
+func (s *WriteStats) recordSuccess(status int, bytes int64, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requestsByStatus[status]++
+	s.bytesSent += bytes
+	s.latencies = append(s.latencies, latency)
+}
+
+func (s *WriteStats) recordError() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors++
+}
+
+// Report is a point-in-time summary produced by WriteStats.Summarize.
+type Report struct {
+	Requests         int
+	RequestsByStatus map[int]int
+	Errors           int
+	BytesSent        int64
+	Elapsed          time.Duration
+	ThroughputPerSec float64
+	P50, P95, P99    time.Duration
+}
+
+// Summarize computes throughput and latency percentiles over the elapsed
+// duration.
+func (s *WriteStats) Summarize(elapsed time.Duration) Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byStatus := make(map[int]int, len(s.requestsByStatus))
+	requests := 0
+	for status, n := range s.requestsByStatus {
+		byStatus[status] = n
+		requests += n
+	}
+
+	r := Report{
+		Requests:         requests,
+		RequestsByStatus: byStatus,
+		Errors:           s.errors,
+		BytesSent:        s.bytesSent,
+		Elapsed:          elapsed,
+	}
+	if elapsed > 0 {
+		r.ThroughputPerSec = float64(requests) / elapsed.Seconds()
+	}
+
+	if len(s.latencies) == 0 {
+		return r
+	}
+	sorted := make([]time.Duration, len(s.latencies))
+	copy(sorted, s.latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	r.P50 = percentile(sorted, 0.50)
+	r.P95 = percentile(sorted, 0.95)
+	r.P99 = percentile(sorted, 0.99)
+	return r
+}
+
+// percentile returns the pth percentile (0 < p <= 1) of a slice already
+// sorted in ascending order.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
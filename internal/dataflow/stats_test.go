@@ -0,0 +1,75 @@
+package dataflow
+
+import (
+	"testing"
+	"time"
+)
+
+func durations(ms ...int) []time.Duration {
+	d := make([]time.Duration, len(ms))
+	for i, m := range ms {
+		d[i] = time.Duration(m) * time.Millisecond
+	}
+	return d
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := durations(10, 20, 30, 40, 50, 60, 70, 80, 90, 100)
+
+	cases := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{0.50, 50 * time.Millisecond},
+		{0.95, 100 * time.Millisecond},
+		{0.99, 100 * time.Millisecond},
+		{0.10, 10 * time.Millisecond},
+	}
+	for _, c := range cases {
+		if got := percentile(sorted, c.p); got != c.want {
+			t.Errorf("percentile(sorted, %v) = %v, want %v", c.p, got, c.want)
+		}
+	}
+}
+
+func TestPercentileEmpty(t *testing.T) {
+	if got := percentile(nil, 0.50); got != 0 {
+		t.Errorf("percentile(nil, 0.50) = %v, want 0", got)
+	}
+}
+
+func TestWriteStatsSummarize(t *testing.T) {
+	s := newWriteStats()
+	s.recordSuccess(204, 100, 10*time.Millisecond)
+	s.recordSuccess(204, 200, 20*time.Millisecond)
+	s.recordError()
+
+	r := s.Summarize(time.Second)
+
+	if r.Requests != 2 {
+		t.Errorf("Requests = %d, want 2", r.Requests)
+	}
+	if r.Errors != 1 {
+		t.Errorf("Errors = %d, want 1", r.Errors)
+	}
+	if r.BytesSent != 300 {
+		t.Errorf("BytesSent = %d, want 300", r.BytesSent)
+	}
+	if r.RequestsByStatus[204] != 2 {
+		t.Errorf("RequestsByStatus[204] = %d, want 2", r.RequestsByStatus[204])
+	}
+	if r.ThroughputPerSec != 2 {
+		t.Errorf("ThroughputPerSec = %v, want 2", r.ThroughputPerSec)
+	}
+	if r.P50 != 10*time.Millisecond {
+		t.Errorf("P50 = %v, want 10ms", r.P50)
+	}
+}
+
+func TestWriteStatsSummarizeNoLatencies(t *testing.T) {
+	s := newWriteStats()
+	r := s.Summarize(time.Second)
+	if r.P50 != 0 || r.P95 != 0 || r.P99 != 0 {
+		t.Errorf("expected zero percentiles with no recorded latencies, got %+v", r)
+	}
+}
@@ -0,0 +1,191 @@
+package dataflow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/platform"
+	phttp "github.com/influxdata/platform/http"
+
+	"github.com/mark-rushakoff/taskdemo/internal/metrics"
+)
+
+// WriteOptions configures the shape, rate, and concurrency of the load that
+// Write generates.
+type WriteOptions struct {
+	Measurement string
+	Tags        map[string]string
+	Fields      []FieldSpec
+
+	// Rate caps the aggregate points/sec across every goroutine. Zero means
+	// unlimited.
+	Rate float64
+
+	// BatchSize is the number of points coalesced into a single write
+	// request.
+	BatchSize int
+
+	// Concurrency is the number of goroutines issuing write requests.
+	Concurrency int
+
+	// Duration bounds how long Write runs before returning. Zero means run
+	// until ctx is done.
+	Duration time.Duration
+}
+
+func (o WriteOptions) concurrency() int {
+	if o.Concurrency < 1 {
+		return 1
+	}
+	return o.Concurrency
+}
+
+func (o WriteOptions) batchSize() int {
+	if o.BatchSize < 1 {
+		return 1
+	}
+	return o.BatchSize
+}
+
+// newHTTPClient returns an *http.Client tuned to hold open keepalive
+// connections for a write goroutine, rather than dialing fresh on every
+// batch.
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			MaxIdleConnsPerHost: 4,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// Write generates points according to opts and writes them to the input
+// bucket in batches, fanning out across opts.Concurrency goroutines, until
+// ctx is done or opts.Duration elapses. It returns accumulated throughput
+// and latency statistics.
+func Write(ctx context.Context, s *Services, opts WriteOptions) (*WriteStats, error) {
+	uID, err := s.UserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user %q: %w", s.UserName(), err)
+	}
+
+	bn := s.BucketInName()
+	on := s.OrgName()
+	bInID, err := s.BucketID(ctx, bn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bucket %q: %w", bn, err)
+	}
+
+	token, err := s.Token(ctx, uID, platform.WriteBucketPermission(bInID))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find existing auth for user %q to write to bucket %q: %w", s.UserName(), bn, err)
+	}
+
+	if opts.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Duration)
+		defer cancel()
+	}
+
+	writeURL := s.APIEndpoint + "/api/v2/write?org=" + url.QueryEscape(on) + "&bucket=" + url.QueryEscape(bn)
+
+	limiter := newRateLimiter(opts.Rate)
+	defer limiter.Stop()
+
+	stats := newWriteStats()
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.concurrency(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			writeLoop(ctx, writeURL, token, opts, limiter, stats)
+		}()
+	}
+	wg.Wait()
+
+	report := stats.Summarize(time.Since(start))
+	s.Log().Infow("Finished writing",
+		"points", report.Requests*opts.batchSize(),
+		"bytes", report.BytesSent,
+		"elapsed", report.Elapsed,
+		"pointsPerSec", report.ThroughputPerSec*float64(opts.batchSize()),
+		"p50", report.P50, "p95", report.P95, "p99", report.P99,
+		"errors", report.Errors, "statuses", report.RequestsByStatus)
+
+	if ctx.Err() != nil && ctx.Err() != context.DeadlineExceeded {
+		return stats, ctx.Err()
+	}
+	return stats, nil
+}
+
+func writeLoop(ctx context.Context, writeURL, token string, opts WriteOptions, limiter *rateLimiter, stats *WriteStats) {
+	client := newHTTPClient()
+
+	for {
+		var b strings.Builder
+		for i := 0; i < opts.batchSize(); i++ {
+			if err := limiter.Wait(ctx); err != nil {
+				return
+			}
+			writeLine(&b, opts)
+		}
+		body := b.String()
+
+		req, err := http.NewRequestWithContext(ctx, "POST", writeURL, strings.NewReader(body))
+		if err != nil {
+			stats.recordError()
+			return
+		}
+		req.Header.Set("User-Agent", "demo")
+		phttp.SetToken(token, req)
+
+		done := metrics.Track("write")
+		reqStart := time.Now()
+		resp, err := client.Do(req)
+		latency := time.Since(reqStart)
+		done()
+		if err != nil {
+			stats.recordError()
+			metrics.Observe("write", -1, 0, latency)
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+		resp.Body.Close()
+		stats.recordSuccess(resp.StatusCode, int64(len(body)), latency)
+		metrics.Observe("write", resp.StatusCode, int64(len(body)), latency)
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
+
+// writeLine appends a single line-protocol point to b.
+func writeLine(b *strings.Builder, opts WriteOptions) {
+	b.WriteString(opts.Measurement)
+	for k, v := range opts.Tags {
+		b.WriteByte(',')
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(v)
+	}
+	b.WriteByte(' ')
+	for i, f := range opts.Fields {
+		if i != 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(b, "%s=%v", f.Name, f.Gen.Next())
+	}
+	b.WriteByte('\n')
+}
@@ -0,0 +1,36 @@
+// Package logging builds the structured logger shared by every taskdemo
+// command.
+package logging
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// New builds a logger writing at level ("debug", "info", "warn", "error")
+// in either "json" or "console" format.
+func New(format, level string) (*zap.SugaredLogger, error) {
+	var cfg zap.Config
+	switch format {
+	case "json":
+		cfg = zap.NewProductionConfig()
+	case "console", "":
+		cfg = zap.NewDevelopmentConfig()
+	default:
+		return nil, fmt.Errorf("unknown log format %q: want \"json\" or \"console\"", format)
+	}
+
+	var lvl zapcore.Level
+	if err := lvl.Set(level); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+	cfg.Level = zap.NewAtomicLevelAt(lvl)
+
+	l, err := cfg.Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build logger: %w", err)
+	}
+	return l.Sugar(), nil
+}
@@ -0,0 +1,90 @@
+// Package metrics exposes the Prometheus instrumentation shared by every
+// taskdemo command that issues requests against the platform API.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// RequestsTotal counts requests by the command that issued them
+	// ("write", "read", "downsample") and the outcome: an HTTP status code
+	// on success, or "error" when the request itself failed.
+	RequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "taskdemo_write_requests_total",
+		Help: "Total number of requests issued to the platform API, by command and status.",
+	}, []string{"command", "status"})
+
+	// DurationSeconds observes request latency by command.
+	DurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "taskdemo_write_duration_seconds",
+		Help:    "Duration of requests issued to the platform API, by command.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"command"})
+
+	// BytesTotal counts request body bytes sent by command.
+	BytesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "taskdemo_write_bytes_total",
+		Help: "Total request body bytes sent to the platform API, by command.",
+	}, []string{"command"})
+
+	// InFlight tracks the number of requests currently outstanding, by
+	// command.
+	InFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "taskdemo_write_in_flight_requests",
+		Help: "Number of requests currently in flight, by command.",
+	}, []string{"command"})
+)
+
+func init() {
+	prometheus.MustRegister(RequestsTotal, DurationSeconds, BytesTotal, InFlight)
+}
+
+// Observe records one request's outcome for command: status is an HTTP
+// status code, or a negative number if the request failed before
+// receiving one.
+func Observe(command string, status int, bytes int64, latency time.Duration) {
+	statusLabel := "error"
+	if status > 0 {
+		statusLabel = fmt.Sprintf("%d", status)
+	}
+	RequestsTotal.WithLabelValues(command, statusLabel).Inc()
+	DurationSeconds.WithLabelValues(command).Observe(latency.Seconds())
+	BytesTotal.WithLabelValues(command).Add(float64(bytes))
+}
+
+// Track increments the in-flight gauge for command and returns a func that
+// decrements it; call it via defer around the request.
+func Track(command string) func() {
+	InFlight.WithLabelValues(command).Inc()
+	return func() { InFlight.WithLabelValues(command).Dec() }
+}
+
+// Serve starts an HTTP server exposing /metrics on addr. The caller is
+// responsible for calling Shutdown on the returned server.
+func Serve(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	srv := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fmt.Printf("metrics server on %s stopped: %v\n", addr, err)
+		}
+	}()
+	return srv
+}
+
+// Shutdown is a convenience wrapper around http.Server.Shutdown with a
+// bounded grace period, for use in a deferred call.
+func Shutdown(srv *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = srv.Shutdown(ctx)
+}
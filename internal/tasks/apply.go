@@ -0,0 +1,105 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/influxdata/platform"
+	phttp "github.com/influxdata/platform/http"
+
+	"github.com/mark-rushakoff/taskdemo/internal/dataflow"
+)
+
+// ApplyOptions configures Apply.
+type ApplyOptions struct {
+	// Print, when set, writes each spec's generated Flux to w instead of
+	// submitting it.
+	Print bool
+}
+
+// Apply creates or updates the tasks described by specs. A spec whose name
+// matches an existing task updates that task's Flux rather than creating a
+// duplicate.
+func Apply(ctx context.Context, s *dataflow.Services, specs []Spec, opts ApplyOptions, w io.Writer) error {
+	on := s.OrgName()
+
+	if opts.Print {
+		for _, spec := range specs {
+			f, err := spec.Flux(on)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "# %s\n%s\n\n", spec.Name, f)
+		}
+		return nil
+	}
+
+	oID, err := s.OrgID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find org %q: %w", s.OrgName(), err)
+	}
+	uID, err := s.UserID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find user %q: %w", s.UserName(), err)
+	}
+
+	for _, spec := range specs {
+		f, err := spec.Flux(on)
+		if err != nil {
+			return err
+		}
+
+		bInID, err := s.BucketID(ctx, spec.SourceBucket)
+		if err != nil {
+			return fmt.Errorf("failed to find bucket %q: %w", spec.SourceBucket, err)
+		}
+		bOutID, err := s.BucketID(ctx, spec.DestBucket)
+		if err != nil {
+			return fmt.Errorf("failed to find bucket %q: %w", spec.DestBucket, err)
+		}
+
+		token, err := s.Token(ctx, uID,
+			platform.ReadBucketPermission(bInID),
+			platform.WriteBucketPermission(bOutID),
+			platform.Permission{Action: platform.CreateAction, Resource: platform.TaskResource(oID)},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to find existing auth for user %q to read from bucket %q, write to bucket %q, and create tasks in org %s: %w",
+				s.UserName(), spec.SourceBucket, spec.DestBucket, oID, err)
+		}
+
+		ts := phttp.TaskService{Addr: s.APIEndpoint, Token: token}
+
+		existing, _, err := ts.FindTasks(ctx, platform.TaskFilter{
+			Organization: &oID,
+			Name:         &spec.Name,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to look up existing task %q: %w", spec.Name, err)
+		}
+
+		switch len(existing) {
+		case 0:
+			t := &platform.Task{
+				Organization: oID,
+				Owner:        platform.User{ID: uID},
+				Flux:         f,
+			}
+			if err := ts.CreateTask(ctx, t); err != nil {
+				return fmt.Errorf("failed to create task %q: %w", spec.Name, err)
+			}
+			s.Log().Infow("Created task", "name", spec.Name, "id", t.ID)
+		case 1:
+			id := existing[0].ID
+			if _, err := ts.UpdateTask(ctx, id, platform.TaskUpdate{Flux: &f}); err != nil {
+				return fmt.Errorf("failed to update task %q: %w", spec.Name, err)
+			}
+			s.Log().Infow("Updated task", "name", spec.Name, "id", id)
+		default:
+			return fmt.Errorf("found %d existing tasks named %q; expected at most one", len(existing), spec.Name)
+		}
+	}
+
+	return nil
+}
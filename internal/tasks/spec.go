@@ -0,0 +1,152 @@
+package tasks
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Spec declaratively describes a downsampling task, as read from a
+// `taskdemo apply -f` file.
+type Spec struct {
+	Name string `yaml:"name"`
+
+	// Exactly one of Every or Cron must be set.
+	Every  string `yaml:"every"`
+	Cron   string `yaml:"cron"`
+	Offset string `yaml:"offset"`
+
+	SourceBucket string `yaml:"source_bucket"`
+	DestBucket   string `yaml:"dest_bucket"`
+
+	Window    string   `yaml:"window"`
+	Aggregate string   `yaml:"aggregate"`
+	GroupBy   []string `yaml:"group_by"`
+}
+
+// ParseSpecFile reads one or more Specs from a YAML file, either a single
+// mapping document or a `---`-separated list of them.
+func ParseSpecFile(path string) ([]Spec, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var specs []Spec
+	dec := yaml.NewDecoder(strings.NewReader(string(raw)))
+	for {
+		var s Spec
+		if err := dec.Decode(&s); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		specs = append(specs, s)
+	}
+
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("%s contained no task definitions", path)
+	}
+	for i, s := range specs {
+		if err := s.validate(); err != nil {
+			return nil, fmt.Errorf("task %d (%q) in %s: %w", i, s.Name, path, err)
+		}
+	}
+	return specs, nil
+}
+
+func (s Spec) validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if (s.Every == "") == (s.Cron == "") {
+		return fmt.Errorf("exactly one of every or cron is required")
+	}
+	if s.SourceBucket == "" {
+		return fmt.Errorf("source_bucket is required")
+	}
+	if s.DestBucket == "" {
+		return fmt.Errorf("dest_bucket is required")
+	}
+	if s.Window == "" {
+		return fmt.Errorf("window is required")
+	}
+	if s.Aggregate == "" {
+		return fmt.Errorf("aggregate is required")
+	}
+	return nil
+}
+
+// Flux generates the Flux source for the task described by s.
+func (s Spec) Flux(orgName string) (string, error) {
+	aggCall, err := aggregateCall(s.Aggregate)
+	if err != nil {
+		return "", fmt.Errorf("task %q: %w", s.Name, err)
+	}
+
+	var opts strings.Builder
+	fmt.Fprintf(&opts, "name: %q", s.Name)
+	if s.Every != "" {
+		fmt.Fprintf(&opts, ", every: %s", s.Every)
+	} else {
+		fmt.Fprintf(&opts, ", cron: %q", s.Cron)
+	}
+	if s.Offset != "" {
+		fmt.Fprintf(&opts, ", offset: %s", s.Offset)
+	}
+
+	var groupBy string
+	if len(s.GroupBy) > 0 {
+		cols := make([]string, len(s.GroupBy))
+		for i, c := range s.GroupBy {
+			cols[i] = fmt.Sprintf("%q", c)
+		}
+		groupBy = fmt.Sprintf(" |> group(columns: [%s])", strings.Join(cols, ", "))
+	}
+
+	f := fmt.Sprintf(
+		`option task = { %s }
+from(bucket: %q)
+	|> range(start: -%s)%s
+	|> %s
+	|> to(bucket: %q, org: %q)`,
+		opts.String(), s.SourceBucket, s.Window, groupBy, aggCall, s.DestBucket, orgName,
+	)
+	return f, nil
+}
+
+// aggregateCall renders the Flux call for an aggregate spec such as "mean"
+// or "percentile:95".
+func aggregateCall(aggregate string) (string, error) {
+	var name, arg string
+	hasArg := false
+	if i := strings.IndexByte(aggregate, ':'); i >= 0 {
+		name, arg, hasArg = aggregate[:i], aggregate[i+1:], true
+	} else {
+		name = aggregate
+	}
+
+	switch name {
+	case "mean", "last", "max", "sum":
+		if hasArg {
+			return "", fmt.Errorf("aggregate %q does not take an argument", name)
+		}
+		return name + "()", nil
+	case "percentile":
+		if !hasArg {
+			return "", fmt.Errorf("aggregate %q requires a percentile argument, e.g. percentile:95", name)
+		}
+		p, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid percentile %q: %w", arg, err)
+		}
+		return fmt.Sprintf("quantile(q: %.12g)", p/100), nil
+	default:
+		return "", fmt.Errorf("unknown aggregate %q", name)
+	}
+}
@@ -0,0 +1,116 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAggregateCall(t *testing.T) {
+	cases := []struct {
+		name    string
+		agg     string
+		want    string
+		wantErr bool
+	}{
+		{name: "mean", agg: "mean", want: "mean()"},
+		{name: "last", agg: "last", want: "last()"},
+		{name: "max", agg: "max", want: "max()"},
+		{name: "sum", agg: "sum", want: "sum()"},
+		{name: "percentile", agg: "percentile:95", want: "quantile(q: 0.95)"},
+		{name: "percentile with decimal", agg: "percentile:99.9", want: "quantile(q: 0.999)"},
+		{name: "mean with unwanted arg", agg: "mean:5", wantErr: true},
+		{name: "percentile without arg", agg: "percentile", wantErr: true},
+		{name: "percentile with bad arg", agg: "percentile:abc", wantErr: true},
+		{name: "unknown aggregate", agg: "median", wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := aggregateCall(c.agg)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("aggregateCall(%q) = %q, nil, want error", c.agg, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("aggregateCall(%q) returned unexpected error: %v", c.agg, err)
+			}
+			if got != c.want {
+				t.Errorf("aggregateCall(%q) = %q, want %q", c.agg, got, c.want)
+			}
+		})
+	}
+}
+
+func TestSpecFlux(t *testing.T) {
+	s := Spec{
+		Name:         "downsample-temp",
+		Every:        "1m",
+		SourceBucket: "in",
+		DestBucket:   "out",
+		Window:       "1m",
+		Aggregate:    "mean",
+		GroupBy:      []string{"host", "region"},
+	}
+
+	f, err := s.Flux("myorg")
+	if err != nil {
+		t.Fatalf("Flux returned unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		`name: "downsample-temp"`,
+		"every: 1m",
+		`from(bucket: "in")`,
+		`range(start: -1m)`,
+		`group(columns: ["host", "region"])`,
+		"mean()",
+		`to(bucket: "out", org: "myorg")`,
+	} {
+		if !strings.Contains(f, want) {
+			t.Errorf("Flux() = %q, want it to contain %q", f, want)
+		}
+	}
+}
+
+func TestSpecFluxCron(t *testing.T) {
+	s := Spec{
+		Name:         "downsample-temp",
+		Cron:         "0 * * * *",
+		SourceBucket: "in",
+		DestBucket:   "out",
+		Window:       "1h",
+		Aggregate:    "percentile:95",
+	}
+
+	f, err := s.Flux("myorg")
+	if err != nil {
+		t.Fatalf("Flux returned unexpected error: %v", err)
+	}
+
+	if !strings.Contains(f, `cron: "0 * * * *"`) {
+		t.Errorf("Flux() = %q, want it to contain the cron option", f)
+	}
+	if !strings.Contains(f, "quantile(q: 0.95)") {
+		t.Errorf("Flux() = %q, want it to contain the percentile call", f)
+	}
+	if strings.Contains(f, "group(") {
+		t.Errorf("Flux() = %q, want no group() call when GroupBy is empty", f)
+	}
+}
+
+func TestSpecFluxInvalidAggregate(t *testing.T) {
+	s := Spec{
+		Name:         "bad",
+		Every:        "1m",
+		SourceBucket: "in",
+		DestBucket:   "out",
+		Window:       "1m",
+		Aggregate:    "median",
+	}
+
+	if _, err := s.Flux("myorg"); err == nil {
+		t.Fatal("Flux() with an unknown aggregate returned nil error, want one")
+	}
+}
@@ -0,0 +1,66 @@
+// Package tasks creates the InfluxDB task that continually downsamples the
+// demo's input bucket into its output bucket.
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/platform"
+	phttp "github.com/influxdata/platform/http"
+
+	"github.com/mark-rushakoff/taskdemo/internal/dataflow"
+)
+
+// Create installs a task that runs every cadence, taking the last point
+// written to the input bucket and writing it to the output bucket.
+func Create(ctx context.Context, s *dataflow.Services, cadence time.Duration) error {
+	oID, err := s.OrgID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find org %q: %w", s.OrgName(), err)
+	}
+	bInID, err := s.BucketID(ctx, s.BucketInName())
+	if err != nil {
+		return fmt.Errorf("failed to find bucket %q: %w", s.BucketInName(), err)
+	}
+	bOutID, err := s.BucketID(ctx, s.BucketOutName())
+	if err != nil {
+		return fmt.Errorf("failed to find bucket %q: %w", s.BucketOutName(), err)
+	}
+	uID, err := s.UserID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find user %q: %w", s.UserName(), err)
+	}
+
+	token, err := s.Token(ctx, uID,
+		platform.ReadBucketPermission(bInID),
+		platform.WriteBucketPermission(bOutID),
+		platform.Permission{Action: platform.CreateAction, Resource: platform.TaskResource(oID)},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to find existing auth for user %q to read from bucket %q, write to bucket %q, and create tasks in org %s: %w",
+			s.UserName(), s.BucketInName(), s.BucketOutName(), oID, err)
+	}
+
+	taskName := fmt.Sprintf("demo-%d", time.Now().Unix())
+	f := fmt.Sprintf(
+		`option task = { name: %q, every: %s } from(bucket:%q) |> range(start:-%s) |> last() |> to(bucket:%q, org:%q) |> yield()`,
+		taskName, cadence, s.BucketInName(), cadence, s.BucketOutName(), s.OrgName(),
+	)
+
+	ts := phttp.TaskService{Addr: s.APIEndpoint, Token: token}
+	t := &platform.Task{
+		Organization: oID,
+		Owner: platform.User{
+			ID: uID,
+		},
+		Flux: f,
+	}
+	if err := ts.CreateTask(ctx, t); err != nil {
+		return fmt.Errorf("failed to create task: %w", err)
+	}
+
+	s.Log().Infow("Created task", "name", taskName, "id", t.ID)
+	return nil
+}
@@ -0,0 +1,64 @@
+package verify
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// aggregate computes the named aggregate (e.g. "mean", "percentile:95")
+// over values, matching the aggregate functions `taskdemo apply` can
+// generate Flux for.
+func aggregate(name string, values []float64) (float64, error) {
+	if len(values) == 0 {
+		return 0, fmt.Errorf("no values to aggregate")
+	}
+
+	fn, arg := name, ""
+	if i := strings.IndexByte(name, ':'); i >= 0 {
+		fn, arg = name[:i], name[i+1:]
+	}
+
+	switch fn {
+	case "last":
+		return values[len(values)-1], nil
+	case "max":
+		m := values[0]
+		for _, v := range values[1:] {
+			if v > m {
+				m = v
+			}
+		}
+		return m, nil
+	case "sum":
+		var total float64
+		for _, v := range values {
+			total += v
+		}
+		return total, nil
+	case "mean":
+		var total float64
+		for _, v := range values {
+			total += v
+		}
+		return total / float64(len(values)), nil
+	case "percentile":
+		if arg == "" {
+			return 0, fmt.Errorf("aggregate %q requires a percentile argument, e.g. percentile:95", fn)
+		}
+		p, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid percentile %q: %w", arg, err)
+		}
+		sorted := append([]float64(nil), values...)
+		sort.Float64s(sorted)
+		idx := int(p / 100 * float64(len(sorted)))
+		if idx >= len(sorted) {
+			idx = len(sorted) - 1
+		}
+		return sorted[idx], nil
+	default:
+		return 0, fmt.Errorf("unknown aggregate %q", fn)
+	}
+}
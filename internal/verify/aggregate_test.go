@@ -0,0 +1,53 @@
+package verify
+
+import "testing"
+
+func TestAggregate(t *testing.T) {
+	values := []float64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}
+
+	cases := []struct {
+		name string
+		agg  string
+		want float64
+	}{
+		{name: "last", agg: "last", want: 100},
+		{name: "max", agg: "max", want: 100},
+		{name: "sum", agg: "sum", want: 550},
+		{name: "mean", agg: "mean", want: 55},
+		{name: "percentile 50", agg: "percentile:50", want: 60},
+		{name: "percentile 95", agg: "percentile:95", want: 100},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := aggregate(c.agg, values)
+			if err != nil {
+				t.Fatalf("aggregate(%q, ...) returned unexpected error: %v", c.agg, err)
+			}
+			if got != c.want {
+				t.Errorf("aggregate(%q, ...) = %v, want %v", c.agg, got, c.want)
+			}
+		})
+	}
+}
+
+func TestAggregateErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		agg    string
+		values []float64
+	}{
+		{name: "no values", agg: "mean", values: nil},
+		{name: "percentile without arg", agg: "percentile", values: []float64{1}},
+		{name: "percentile with bad arg", agg: "percentile:abc", values: []float64{1}},
+		{name: "unknown aggregate", agg: "median", values: []float64{1}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := aggregate(c.agg, c.values); err == nil {
+				t.Fatalf("aggregate(%q, %v) returned nil error, want one", c.agg, c.values)
+			}
+		})
+	}
+}
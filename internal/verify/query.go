@@ -0,0 +1,105 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/platform"
+	phttp "github.com/influxdata/platform/http"
+	"github.com/influxdata/platform/query"
+)
+
+// point is a single (_time, _value) observation read back from a Flux
+// query's CSV output.
+type point struct {
+	Time  time.Time
+	Value float64
+}
+
+// queryPoints runs a range query against bucketName, keeping only the
+// _time and _value columns, and returns every observation across every
+// table in the result.
+func queryPoints(ctx context.Context, apiEndpoint, token string, oID platform.ID, bucketName string, start time.Duration) ([]point, error) {
+	q := fmt.Sprintf(
+		`from(bucket:%q) |> range(start: -%s) |> keep(columns: ["_time", "_value"])`,
+		bucketName, start,
+	)
+
+	fqs := phttp.FluxQueryService{Addr: apiEndpoint, Token: token}
+	it, err := fqs.Query(ctx, &query.Request{
+		// FluxQueryService reads only Authorization.Token off the request to set
+		// the bearer header; the server re-checks permissions against the token
+		// itself, so a full *platform.Authorization isn't needed here.
+		Authorization:  &platform.Authorization{Token: token},
+		OrganizationID: oID,
+
+		Compiler: lang.FluxCompiler{Query: q},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bucket %q: %w", bucketName, err)
+	}
+
+	var buf bytes.Buffer
+	enc := csv.NewMultiResultEncoder(csv.DefaultEncoderConfig())
+	if _, err := enc.Encode(&buf, it); err != nil {
+		return nil, fmt.Errorf("failed to encode csv for bucket %q: %w", bucketName, err)
+	}
+
+	return parsePoints(buf.String()), nil
+}
+
+// parsePoints scans an annotated Flux CSV result, extracting every _time
+// and _value pair across every table. Unparseable or unrelated rows are
+// skipped rather than erroring, since annotation and table-boundary rows
+// share the same comma-separated shape.
+func parsePoints(csvData string) []point {
+	var points []point
+	timeIdx, valueIdx := -1, -1
+
+	for _, line := range strings.Split(csvData, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			// Blank line marks a table boundary; the next table repeats
+			// its own header.
+			timeIdx, valueIdx = -1, -1
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		cols := strings.Split(line, ",")
+		if timeIdx == -1 {
+			for i, c := range cols {
+				switch c {
+				case "_time":
+					timeIdx = i
+				case "_value":
+					valueIdx = i
+				}
+			}
+			continue
+		}
+
+		if timeIdx >= len(cols) || valueIdx >= len(cols) {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, cols[timeIdx])
+		if err != nil {
+			continue
+		}
+		v, err := strconv.ParseFloat(cols[valueIdx], 64)
+		if err != nil {
+			continue
+		}
+		points = append(points, point{Time: t, Value: v})
+	}
+
+	return points
+}
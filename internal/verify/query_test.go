@@ -0,0 +1,62 @@
+package verify
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePoints(t *testing.T) {
+	csvData := "" +
+		"#datatype,string,long,dateTime:RFC3339,double\n" +
+		"#group,false,false,false,false\n" +
+		"#default,_result,,,\n" +
+		",result,table,_time,_value\n" +
+		",,0,2020-01-01T00:00:00Z,1\n" +
+		",,0,2020-01-01T00:01:00Z,2\n" +
+		"\n" +
+		",result,table,_time,_value\n" +
+		",,1,2020-01-01T00:02:00Z,3\n"
+
+	points := parsePoints(csvData)
+
+	want := []point{
+		{Time: mustParseTime(t, "2020-01-01T00:00:00Z"), Value: 1},
+		{Time: mustParseTime(t, "2020-01-01T00:01:00Z"), Value: 2},
+		{Time: mustParseTime(t, "2020-01-01T00:02:00Z"), Value: 3},
+	}
+
+	if len(points) != len(want) {
+		t.Fatalf("parsePoints returned %d points, want %d: %+v", len(points), len(want), points)
+	}
+	for i, p := range points {
+		if !p.Time.Equal(want[i].Time) || p.Value != want[i].Value {
+			t.Errorf("points[%d] = %+v, want %+v", i, p, want[i])
+		}
+	}
+}
+
+func TestParsePointsSkipsUnparseableRows(t *testing.T) {
+	csvData := "" +
+		"#datatype,string,long,dateTime:RFC3339,double\n" +
+		",result,table,_time,_value\n" +
+		",,0,not-a-time,1\n" +
+		",,0,2020-01-01T00:00:00Z,not-a-number\n" +
+		",,0,2020-01-01T00:00:00Z,5\n"
+
+	points := parsePoints(csvData)
+	if len(points) != 1 {
+		t.Fatalf("parsePoints returned %d points, want 1: %+v", len(points), points)
+	}
+	if points[0].Value != 5 {
+		t.Errorf("points[0].Value = %v, want 5", points[0].Value)
+	}
+}
+
+func mustParseTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	ts, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatalf("failed to parse time %q: %v", s, err)
+	}
+	return ts
+}
@@ -0,0 +1,155 @@
+// Package verify checks that a bucket's downsampled output matches the
+// aggregate taskdemo expects, computed in-process from the raw input data.
+package verify
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/influxdata/platform"
+
+	"github.com/mark-rushakoff/taskdemo/internal/dataflow"
+)
+
+// Options configures Run.
+type Options struct {
+	// Window is the duration of each downsample window to check.
+	Window time.Duration
+
+	// NumWindows is how many of the most recent windows to check.
+	NumWindows int
+
+	// Aggregate is the aggregate function the task is expected to have
+	// applied, e.g. "last", "mean", or "percentile:95".
+	Aggregate string
+
+	// Tolerance is the maximum allowed absolute difference between the
+	// expected and actual aggregate for a window to pass.
+	Tolerance float64
+}
+
+func (o Options) numWindows() int {
+	if o.NumWindows < 1 {
+		return 1
+	}
+	return o.NumWindows
+}
+
+// WindowResult reports whether a single window's downsampled output
+// matched the in-process aggregate of its input data.
+type WindowResult struct {
+	Start, End       time.Time
+	Expected, Actual float64
+	HasActual        bool
+	Pass             bool
+}
+
+// Report summarizes the outcome of checking every window.
+type Report struct {
+	Windows []WindowResult
+	Pass    bool
+}
+
+// Run queries the input and output buckets over opts.NumWindows most
+// recent opts.Window-sized windows, computes the expected aggregate from
+// the raw input data in-process, and compares it against the task's actual
+// output.
+func Run(ctx context.Context, s *dataflow.Services, opts Options) (Report, error) {
+	oID, err := s.OrgID(ctx)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to find org %q: %w", s.OrgName(), err)
+	}
+	uID, err := s.UserID(ctx)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to find user %q: %w", s.UserName(), err)
+	}
+	bInID, err := s.BucketID(ctx, s.BucketInName())
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to find bucket %q: %w", s.BucketInName(), err)
+	}
+	bOutID, err := s.BucketID(ctx, s.BucketOutName())
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to find bucket %q: %w", s.BucketOutName(), err)
+	}
+
+	tokenIn, err := s.Token(ctx, uID, platform.ReadBucketPermission(bInID))
+	if err != nil {
+		return Report{}, fmt.Errorf("unable to find existing auth for user %q to read from bucket %q: %w",
+			s.UserName(), s.BucketInName(), err)
+	}
+	tokenOut, err := s.Token(ctx, uID, platform.ReadBucketPermission(bOutID))
+	if err != nil {
+		return Report{}, fmt.Errorf("unable to find existing auth for user %q to read from bucket %q: %w",
+			s.UserName(), s.BucketOutName(), err)
+	}
+
+	totalRange := opts.Window * time.Duration(opts.numWindows())
+
+	in, err := queryPoints(ctx, s.APIEndpoint, tokenIn, oID, s.BucketInName(), totalRange)
+	if err != nil {
+		return Report{}, err
+	}
+	out, err := queryPoints(ctx, s.APIEndpoint, tokenOut, oID, s.BucketOutName(), totalRange)
+	if err != nil {
+		return Report{}, err
+	}
+
+	now := time.Now()
+	report := Report{Pass: true}
+
+	for i := opts.numWindows() - 1; i >= 0; i-- {
+		end := now.Add(-time.Duration(i) * opts.Window)
+		start := end.Add(-opts.Window)
+
+		var inValues []float64
+		for _, p := range in {
+			if !p.Time.Before(start) && p.Time.Before(end) {
+				inValues = append(inValues, p.Value)
+			}
+		}
+
+		wr := WindowResult{Start: start, End: end}
+		if len(inValues) == 0 {
+			s.Log().Infow("Window has no input data; skipping", "start", start.Format(time.RFC3339), "end", end.Format(time.RFC3339))
+			continue
+		}
+
+		expected, err := aggregate(opts.Aggregate, inValues)
+		if err != nil {
+			return Report{}, err
+		}
+		wr.Expected = expected
+
+		var actual float64
+		for _, p := range out {
+			if !p.Time.Before(start) && p.Time.Before(end) {
+				wr.HasActual = true
+				actual = p.Value
+			}
+		}
+		wr.Actual = actual
+
+		wr.Pass = wr.HasActual && math.Abs(expected-actual) <= opts.Tolerance
+		if !wr.Pass {
+			report.Pass = false
+		}
+
+		if wr.HasActual {
+			s.Log().Infow("Checked window", "start", start.Format(time.RFC3339), "end", end.Format(time.RFC3339),
+				"expected", wr.Expected, "actual", wr.Actual, "pass", wr.Pass)
+		} else {
+			s.Log().Infow("Checked window", "start", start.Format(time.RFC3339), "end", end.Format(time.RFC3339),
+				"expected", wr.Expected, "actual", nil, "pass", wr.Pass)
+		}
+
+		report.Windows = append(report.Windows, wr)
+	}
+
+	if len(report.Windows) == 0 {
+		return Report{}, fmt.Errorf("no window had any input data over the checked range; is %q bootstrapped and written to?", s.BucketInName())
+	}
+
+	return report, nil
+}